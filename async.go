@@ -0,0 +1,90 @@
+package lcdm_2000_api
+
+import (
+	"errors"
+	"time"
+)
+
+// Event is an unsolicited frame read off the wire while no command is
+// awaiting a response, e.g. a periodic sensor snapshot the device pushes
+// without having been asked for one.
+type Event struct {
+	ReceivedAt time.Time
+	Frame      []byte
+}
+
+// EventSource is implemented by Transporters that can surface unsolicited
+// Events alongside command responses.
+type EventSource interface {
+	Events() <-chan Event
+}
+
+// ErrTransporterClosed is returned by SendCtx when the transporter's reader
+// goroutine has already shut down.
+var ErrTransporterClosed = errors.New("transporter is closed")
+
+type sendResult struct {
+	data []byte
+	err  error
+}
+
+type inflightRequest struct {
+	aduRequest []byte
+	resultCh   chan sendResult
+}
+
+func (t *serialTransporter) Events() <-chan Event {
+	return t.unsolicitedCh
+}
+
+func (t *serialTransporter) startReader() {
+	t.stopCh = make(chan struct{})
+	t.reqCh = make(chan *inflightRequest)
+	t.unsolicitedCh = make(chan Event, 16)
+
+	t.wg.Add(1)
+	go t.readLoop()
+}
+
+func (t *serialTransporter) stopReader() {
+	close(t.stopCh)
+	t.wg.Wait()
+}
+
+// readLoop owns every Read call against the serial port, similar to how HCI
+// stacks split command completion from asynchronous events: it either
+// services the next queued request (exchange) or, while idle, surfaces
+// anything the device pushes unprompted as an Event.
+func (t *serialTransporter) readLoop() {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case <-t.stopCh:
+			return
+		case req := <-t.reqCh:
+			data, err := t.exchange(req.aduRequest)
+			req.resultCh <- sendResult{data: data, err: err}
+		default:
+			t.pollUnsolicited()
+		}
+	}
+}
+
+func (t *serialTransporter) pollUnsolicited() {
+	innerBuf := make([]byte, 256)
+
+	n, err := t.port.Read(innerBuf)
+
+	if err != nil || n == 0 {
+		return
+	}
+
+	event := Event{ReceivedAt: time.Now(), Frame: append([]byte(nil), innerBuf[:n]...)}
+
+	select {
+	case t.unsolicitedCh <- event:
+	default:
+		// best effort: drop the event rather than block the reader
+	}
+}
@@ -0,0 +1,166 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakePort stands in for an open serial port in tests. Read mimics a real
+// port's ReadTimeout behavior: it blocks until data is pushed, the port is
+// closed, or idleTick elapses with nothing to read, in which case it
+// returns (0, nil) just like a real read-timeout.
+type fakePort struct {
+	dataCh   chan []byte
+	writesCh chan []byte
+	closed   chan struct{}
+	idleTick time.Duration
+}
+
+func newFakePort(idleTick time.Duration) *fakePort {
+	return &fakePort{
+		dataCh:   make(chan []byte, 16),
+		writesCh: make(chan []byte, 16),
+		closed:   make(chan struct{}),
+		idleTick: idleTick,
+	}
+}
+
+func (p *fakePort) Read(buf []byte) (int, error) {
+	select {
+	case chunk := <-p.dataCh:
+		return copy(buf, chunk), nil
+	case <-p.closed:
+		return 0, io.EOF
+	case <-time.After(p.idleTick):
+		return 0, nil
+	}
+}
+
+func (p *fakePort) Write(buf []byte) (int, error) {
+	select {
+	case p.writesCh <- append([]byte(nil), buf...):
+	default:
+	}
+
+	return len(buf), nil
+}
+
+func (p *fakePort) Close() error {
+	select {
+	case <-p.closed:
+	default:
+		close(p.closed)
+	}
+
+	return nil
+}
+
+func (p *fakePort) push(chunk []byte) { p.dataCh <- chunk }
+
+// buildFrame assembles a framed response the way lcdmPackager would decode
+// it: header, a leading byte (the response would carry the status code
+// here, but the reader doesn't inspect it), payload, footer and checksum.
+func buildFrame(payload []byte) []byte {
+	body := []byte{ResponseStart, CommunicationIdentify, TextStart, 0x00}
+	body = append(body, payload...)
+	body = append(body, TextEnd)
+
+	return append(body, getChecksum(body))
+}
+
+// TestReaderLoopServicesQueuedRequestPromptly exercises the bug the idle
+// poll used to have: a queued request used to wait behind whatever
+// ReadTimeout the caller configured for the whole port (previously seconds).
+// idlePollInterval now bounds every idle read instead, so the wait is at
+// most a couple of poll ticks regardless of how long a command is willing
+// to wait for its own response.
+func TestReaderLoopServicesQueuedRequestPromptly(t *testing.T) {
+	port := newFakePort(idlePollInterval)
+	defer port.Close()
+
+	tr := &serialTransporter{port: port, stats: newStats(), commandTimeout: 5 * time.Second}
+	tr.startReader()
+	defer tr.stopReader()
+
+	go func() {
+		<-port.writesCh
+		port.push([]byte{byte(AckResponse)})
+		port.push(buildFrame([]byte("hello")))
+	}()
+
+	start := time.Now()
+	data, err := tr.SendCtx(context.Background(), []byte{0x01})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := buildFrame([]byte("hello"))
+	if string(data) != string(want) {
+		t.Fatalf("got %q, want %q", data, want)
+	}
+
+	// exchange() sleeps 200ms after a successful round trip before
+	// returning, so allow for that on top of the poll interval.
+	if elapsed > 200*time.Millisecond+5*idlePollInterval {
+		t.Fatalf("SendCtx took %v, expected the reader to pick it up within a couple of poll intervals", elapsed)
+	}
+}
+
+// TestReaderLoopSurfacesUnsolicitedEvents checks that a frame pushed while
+// no request is in flight is published on Events rather than dropped.
+func TestReaderLoopSurfacesUnsolicitedEvents(t *testing.T) {
+	port := newFakePort(10 * time.Millisecond)
+	defer port.Close()
+
+	tr := &serialTransporter{port: port, stats: newStats()}
+	tr.startReader()
+	defer tr.stopReader()
+
+	frame := buildFrame([]byte("ping"))
+	port.push(frame)
+
+	select {
+	case event := <-tr.Events():
+		if string(event.Frame) != string(frame) {
+			t.Fatalf("got frame %q, want %q", event.Frame, frame)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for unsolicited event")
+	}
+}
+
+// TestSendCtxRespectsCancellation checks that a caller waiting on a reply
+// that will never arrive is released as soon as its context is cancelled,
+// rather than blocking until the reader goroutine times the read out.
+func TestSendCtxRespectsCancellation(t *testing.T) {
+	port := newFakePort(idlePollInterval)
+	defer port.Close()
+
+	// A short commandTimeout keeps this test fast: once ctx is cancelled,
+	// SendCtx returns immediately, but the reader goroutine's in-flight
+	// exchange (which cancellation doesn't abort mid-wire) still has to run
+	// out its read-retry budget before stopReader's deferred wg.Wait() below
+	// can return.
+	tr := &serialTransporter{port: port, stats: newStats(), commandTimeout: 200 * time.Millisecond}
+	tr.startReader()
+	defer tr.stopReader()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := tr.SendCtx(ctx, []byte{0x01})
+	elapsed := time.Since(start)
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if elapsed > 5*idlePollInterval {
+		t.Fatalf("SendCtx took %v to honor a 5ms deadline", elapsed)
+	}
+}
@@ -0,0 +1,454 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ReconnectConfig tunes how AutoReconnectDispenser recovers from a dropped
+// serial link.
+type ReconnectConfig struct {
+	// RetryBackoff returns how long to wait before reconnect attempt
+	// number attempt (1-based). Defaults to a flat 1 second if nil.
+	RetryBackoff func(attempt int) time.Duration
+
+	// MaxAttempts bounds how many times a single reconnect is retried
+	// before giving up. Zero means unlimited.
+	MaxAttempts int
+
+	// ProbeInterval is how long the link may sit idle before a liveness
+	// probe is issued. Zero disables probing.
+	ProbeInterval time.Duration
+
+	// ProbeCommand issues a lightweight request against client to verify
+	// the link is still alive. Defaults to calling RomVersion.
+	ProbeCommand func(client Client) error
+}
+
+var _ Client = (*AutoReconnectDispenser)(nil)
+
+// AutoReconnectDispenser wraps a Client and transparently reopens the
+// underlying serial link when a command fails with a dropped-connection
+// error, mirroring the "open in a loop, sleep on failure, close and retry
+// on read error" pattern common in serial daemons. Cash dispensers
+// routinely drop the USB-serial link when the power cycles or the operator
+// services the tray, so callers shouldn't have to handle that themselves.
+type AutoReconnectDispenser struct {
+	dial   func() (Client, error)
+	config ReconnectConfig
+
+	mu     sync.Mutex
+	client Client
+	closed bool
+
+	// reconnecting, reconnectDone and reconnectErr coordinate concurrent
+	// callers during a reconnect: only one goroutine actually redials, the
+	// rest wait on reconnectDone instead of racing it against the (about to
+	// be closed) stale client, then pick up reconnectErr to learn whether
+	// that round actually succeeded.
+	reconnecting  bool
+	reconnectDone chan struct{}
+	reconnectErr  error
+
+	lastActivity time.Time
+	stopProbe    chan struct{}
+	probeDone    chan struct{}
+}
+
+// NewAutoReconnectDispenser dials once via dial and, if config.ProbeInterval
+// is set, starts probing the link for silent drops while it's idle.
+func NewAutoReconnectDispenser(dial func() (Client, error), config ReconnectConfig) (*AutoReconnectDispenser, error) {
+	if config.RetryBackoff == nil {
+		config.RetryBackoff = func(int) time.Duration { return time.Second }
+	}
+
+	if config.ProbeCommand == nil {
+		config.ProbeCommand = func(c Client) error {
+			_, _, err := c.RomVersion()
+			return err
+		}
+	}
+
+	client, err := dial()
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := &AutoReconnectDispenser{
+		dial:         dial,
+		config:       config,
+		client:       client,
+		lastActivity: time.Now(),
+	}
+
+	if config.ProbeInterval > 0 {
+		d.stopProbe = make(chan struct{})
+		d.probeDone = make(chan struct{})
+		go d.probeLoop()
+	}
+
+	return d, nil
+}
+
+func (d *AutoReconnectDispenser) currentClient() Client {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.client
+}
+
+func (d *AutoReconnectDispenser) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	client := d.client
+	d.mu.Unlock()
+
+	if d.stopProbe != nil {
+		close(d.stopProbe)
+		<-d.probeDone
+	}
+
+	return client.Close()
+}
+
+func (d *AutoReconnectDispenser) Stats() DispenserStats {
+	return d.currentClient().Stats()
+}
+
+func (d *AutoReconnectDispenser) RegisterObserver(fn func(StatEvent)) {
+	d.currentClient().RegisterObserver(fn)
+}
+
+func (d *AutoReconnectDispenser) Events() <-chan Event {
+	return d.currentClient().Events()
+}
+
+func (d *AutoReconnectDispenser) probeLoop() {
+	defer close(d.probeDone)
+
+	ticker := time.NewTicker(d.config.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stopProbe:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			idleFor := time.Since(d.lastActivity)
+			d.mu.Unlock()
+
+			if idleFor < d.config.ProbeInterval {
+				continue
+			}
+
+			_ = d.withReconnect(context.Background(), d.config.ProbeCommand)
+		}
+	}
+}
+
+// isTransientLinkError reports whether err looks like the serial link was
+// dropped (power cycle, cable unplugged, device serviced) rather than a
+// protocol-level rejection that reopening the port wouldn't fix.
+func isTransientLinkError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, syscall.ENXIO) {
+		return true
+	}
+
+	var pathErr *os.PathError
+
+	return errors.As(err, &pathErr)
+}
+
+// withReconnect runs fn against the current client. If fn fails with a
+// transient link error, it triggers a reconnect and retries fn exactly once
+// against the new client. While a reconnect is in flight, other callers
+// block on it instead of racing it against the stale client that's about to
+// be closed out from under them.
+func (d *AutoReconnectDispenser) withReconnect(ctx context.Context, fn func(Client) error) error {
+	client, err := d.awaitClient(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	callErr := fn(client)
+
+	d.mu.Lock()
+	d.lastActivity = time.Now()
+	d.mu.Unlock()
+
+	if !isTransientLinkError(callErr) {
+		return callErr
+	}
+
+	if reconnectErr := d.triggerReconnect(ctx, client); reconnectErr != nil {
+		return reconnectErr
+	}
+
+	newClient, err := d.awaitClient(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return fn(newClient)
+}
+
+// awaitClient returns the current client, waiting out any in-flight
+// reconnect rather than handing back a client that's about to be replaced.
+func (d *AutoReconnectDispenser) awaitClient(ctx context.Context) (Client, error) {
+	for {
+		d.mu.Lock()
+
+		if d.closed {
+			d.mu.Unlock()
+			return nil, errors.New("dispenser is closed")
+		}
+
+		if d.reconnecting {
+			waitCh := d.reconnectDone
+			d.mu.Unlock()
+
+			select {
+			case <-waitCh:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		client := d.client
+		d.mu.Unlock()
+
+		return client, nil
+	}
+}
+
+// triggerReconnect ensures exactly one goroutine redials for a given stale
+// client: the caller that observes staleClient still current performs the
+// reconnect and wakes everyone else waiting on reconnectDone; callers that
+// lose the race, or that find the client has already moved on, just return.
+func (d *AutoReconnectDispenser) triggerReconnect(ctx context.Context, staleClient Client) error {
+	d.mu.Lock()
+
+	if d.reconnecting {
+		waitCh := d.reconnectDone
+		d.mu.Unlock()
+
+		select {
+		case <-waitCh:
+			d.mu.Lock()
+			err := d.reconnectErr
+			d.mu.Unlock()
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if d.client != staleClient {
+		d.mu.Unlock()
+		return nil
+	}
+
+	d.reconnecting = true
+	d.reconnectDone = make(chan struct{})
+	d.mu.Unlock()
+
+	err := d.reconnect(ctx, staleClient)
+
+	d.mu.Lock()
+	d.reconnecting = false
+	d.reconnectErr = err
+	close(d.reconnectDone)
+	d.mu.Unlock()
+
+	return err
+}
+
+// reconnect redials with backoff until config.MaxAttempts is exhausted or
+// ctx is cancelled. old is only closed once a replacement is in hand, so
+// callers waiting in awaitClient never observe a closed client.
+func (d *AutoReconnectDispenser) reconnect(ctx context.Context, old Client) error {
+	for attempt := 1; d.config.MaxAttempts == 0 || attempt <= d.config.MaxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.config.RetryBackoff(attempt)):
+		}
+
+		client, err := d.dial()
+
+		if err == nil {
+			d.mu.Lock()
+			d.client = client
+			d.mu.Unlock()
+
+			_ = old.Close()
+
+			return nil
+		}
+	}
+
+	return errors.New("reconnect: max attempts exceeded")
+}
+
+func (d *AutoReconnectDispenser) Status() (StatusCode, SensorStatus, error) {
+	return d.StatusCtx(context.Background())
+}
+
+func (d *AutoReconnectDispenser) StatusCtx(ctx context.Context) (StatusCode, SensorStatus, error) {
+	var code StatusCode
+	var sensors SensorStatus
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		code, sensors, innerErr = c.StatusCtx(ctx)
+		return innerErr
+	})
+
+	return code, sensors, err
+}
+
+func (d *AutoReconnectDispenser) Reset() error {
+	return d.ResetCtx(context.Background())
+}
+
+func (d *AutoReconnectDispenser) ResetCtx(ctx context.Context) error {
+	return d.withReconnect(ctx, func(c Client) error {
+		return c.ResetCtx(ctx)
+	})
+}
+
+func (d *AutoReconnectDispenser) Purge() error {
+	return d.PurgeCtx(context.Background())
+}
+
+func (d *AutoReconnectDispenser) PurgeCtx(ctx context.Context) error {
+	return d.withReconnect(ctx, func(c Client) error {
+		return c.PurgeCtx(ctx)
+	})
+}
+
+func (d *AutoReconnectDispenser) UpperDispense(count byte) (DispenseResult, error) {
+	return d.UpperDispenseCtx(context.Background(), count)
+}
+
+func (d *AutoReconnectDispenser) UpperDispenseCtx(ctx context.Context, count byte) (DispenseResult, error) {
+	var result DispenseResult
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		result, innerErr = c.UpperDispenseCtx(ctx, count)
+		return innerErr
+	})
+
+	return result, err
+}
+
+func (d *AutoReconnectDispenser) LowerDispense(count byte) (DispenseResult, error) {
+	return d.LowerDispenseCtx(context.Background(), count)
+}
+
+func (d *AutoReconnectDispenser) LowerDispenseCtx(ctx context.Context, count byte) (DispenseResult, error) {
+	var result DispenseResult
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		result, innerErr = c.LowerDispenseCtx(ctx, count)
+		return innerErr
+	})
+
+	return result, err
+}
+
+func (d *AutoReconnectDispenser) Dispense(upperCount byte, lowerCount byte) (DispenseResult, error) {
+	return d.DispenseCtx(context.Background(), upperCount, lowerCount)
+}
+
+func (d *AutoReconnectDispenser) DispenseCtx(ctx context.Context, upperCount byte, lowerCount byte) (DispenseResult, error) {
+	var result DispenseResult
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		result, innerErr = c.DispenseCtx(ctx, upperCount, lowerCount)
+		return innerErr
+	})
+
+	return result, err
+}
+
+func (d *AutoReconnectDispenser) TestDispense(cassette Cassette, count byte) (DispenseResult, error) {
+	return d.TestDispenseCtx(context.Background(), cassette, count)
+}
+
+func (d *AutoReconnectDispenser) TestDispenseCtx(ctx context.Context, cassette Cassette, count byte) (DispenseResult, error) {
+	var result DispenseResult
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		result, innerErr = c.TestDispenseCtx(ctx, cassette, count)
+		return innerErr
+	})
+
+	return result, err
+}
+
+func (d *AutoReconnectDispenser) SensorDiagnostic(cassette Cassette) (SensorStatus, error) {
+	return d.SensorDiagnosticCtx(context.Background(), cassette)
+}
+
+func (d *AutoReconnectDispenser) SensorDiagnosticCtx(ctx context.Context, cassette Cassette) (SensorStatus, error) {
+	var sensors SensorStatus
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		sensors, innerErr = c.SensorDiagnosticCtx(ctx, cassette)
+		return innerErr
+	})
+
+	return sensors, err
+}
+
+func (d *AutoReconnectDispenser) Settings() (Settings, error) {
+	return d.SettingsCtx(context.Background())
+}
+
+func (d *AutoReconnectDispenser) SettingsCtx(ctx context.Context) (Settings, error) {
+	var settings Settings
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		settings, innerErr = c.SettingsCtx(ctx)
+		return innerErr
+	})
+
+	return settings, err
+}
+
+func (d *AutoReconnectDispenser) RomVersion() (string, string, error) {
+	return d.RomVersionCtx(context.Background())
+}
+
+func (d *AutoReconnectDispenser) RomVersionCtx(ctx context.Context) (string, string, error) {
+	var romVersion, buildDate string
+
+	err := d.withReconnect(ctx, func(c Client) error {
+		var innerErr error
+		romVersion, buildDate, innerErr = c.RomVersionCtx(ctx)
+		return innerErr
+	})
+
+	return romVersion, buildDate, err
+}
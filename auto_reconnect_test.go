@@ -0,0 +1,232 @@
+package lcdm_2000_api_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	api "lcdm_2000_api"
+)
+
+// fakeClient is a minimal api.Client stand-in so AutoReconnectDispenser can
+// be exercised without a real serial link. romVersionErr is returned by
+// RomVersionCtx until cleared, letting tests simulate a dropped link.
+type fakeClient struct {
+	romVersionErr error
+	closed        bool
+}
+
+func (f *fakeClient) Status() (api.StatusCode, api.SensorStatus, error) {
+	return f.StatusCtx(context.Background())
+}
+func (f *fakeClient) StatusCtx(ctx context.Context) (api.StatusCode, api.SensorStatus, error) {
+	return 0, api.SensorStatus{}, nil
+}
+func (f *fakeClient) Reset() error                       { return f.ResetCtx(context.Background()) }
+func (f *fakeClient) ResetCtx(ctx context.Context) error { return nil }
+func (f *fakeClient) Purge() error                       { return f.PurgeCtx(context.Background()) }
+func (f *fakeClient) PurgeCtx(ctx context.Context) error { return nil }
+func (f *fakeClient) UpperDispense(count byte) (api.DispenseResult, error) {
+	return f.UpperDispenseCtx(context.Background(), count)
+}
+func (f *fakeClient) UpperDispenseCtx(ctx context.Context, count byte) (api.DispenseResult, error) {
+	return api.DispenseResult{}, nil
+}
+func (f *fakeClient) LowerDispense(count byte) (api.DispenseResult, error) {
+	return f.LowerDispenseCtx(context.Background(), count)
+}
+func (f *fakeClient) LowerDispenseCtx(ctx context.Context, count byte) (api.DispenseResult, error) {
+	return api.DispenseResult{}, nil
+}
+func (f *fakeClient) Dispense(upperCount, lowerCount byte) (api.DispenseResult, error) {
+	return f.DispenseCtx(context.Background(), upperCount, lowerCount)
+}
+func (f *fakeClient) DispenseCtx(ctx context.Context, upperCount, lowerCount byte) (api.DispenseResult, error) {
+	return api.DispenseResult{}, nil
+}
+func (f *fakeClient) TestDispense(cassette api.Cassette, count byte) (api.DispenseResult, error) {
+	return f.TestDispenseCtx(context.Background(), cassette, count)
+}
+func (f *fakeClient) TestDispenseCtx(ctx context.Context, cassette api.Cassette, count byte) (api.DispenseResult, error) {
+	return api.DispenseResult{}, nil
+}
+func (f *fakeClient) SensorDiagnostic(cassette api.Cassette) (api.SensorStatus, error) {
+	return f.SensorDiagnosticCtx(context.Background(), cassette)
+}
+func (f *fakeClient) SensorDiagnosticCtx(ctx context.Context, cassette api.Cassette) (api.SensorStatus, error) {
+	return api.SensorStatus{}, nil
+}
+func (f *fakeClient) Settings() (api.Settings, error) { return f.SettingsCtx(context.Background()) }
+func (f *fakeClient) SettingsCtx(ctx context.Context) (api.Settings, error) {
+	return api.Settings{}, nil
+}
+func (f *fakeClient) RomVersion() (string, string, error) {
+	return f.RomVersionCtx(context.Background())
+}
+func (f *fakeClient) RomVersionCtx(ctx context.Context) (string, string, error) {
+	if f.romVersionErr != nil {
+		return "", "", f.romVersionErr
+	}
+	return "01", "2024", nil
+}
+func (f *fakeClient) Close() error                            { f.closed = true; return nil }
+func (f *fakeClient) Stats() api.DispenserStats               { return api.DispenserStats{} }
+func (f *fakeClient) RegisterObserver(fn func(api.StatEvent)) {}
+func (f *fakeClient) Events() <-chan api.Event                { return nil }
+
+func noBackoff(int) time.Duration { return time.Millisecond }
+
+func TestAutoReconnectRetriesAgainstNewClientAfterTransientError(t *testing.T) {
+	stale := &fakeClient{romVersionErr: io.EOF}
+	fresh := &fakeClient{}
+
+	dialCount := 0
+	dial := func() (api.Client, error) {
+		dialCount++
+		if dialCount == 1 {
+			return stale, nil
+		}
+		return fresh, nil
+	}
+
+	dispenser, err := api.NewAutoReconnectDispenser(dial, api.ReconnectConfig{RetryBackoff: noBackoff})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer dispenser.Close()
+
+	version, _, err := dispenser.RomVersion()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "01" {
+		t.Fatalf("got version %q, want %q", version, "01")
+	}
+	if !stale.closed {
+		t.Fatalf("expected the stale client to be closed after a successful reconnect")
+	}
+}
+
+func TestAutoReconnectGivesUpAfterMaxAttempts(t *testing.T) {
+	stale := &fakeClient{romVersionErr: io.EOF}
+
+	dialCount := 0
+	dial := func() (api.Client, error) {
+		dialCount++
+		if dialCount == 1 {
+			return stale, nil
+		}
+		return nil, errors.New("dial failed")
+	}
+
+	dispenser, err := api.NewAutoReconnectDispenser(dial, api.ReconnectConfig{
+		RetryBackoff: noBackoff,
+		MaxAttempts:  2,
+	})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer dispenser.Close()
+
+	_, _, err = dispenser.RomVersion()
+	if err == nil {
+		t.Fatalf("expected an error once max attempts is exhausted")
+	}
+}
+
+// TestAutoReconnectConcurrentCallersBlockInsteadOfSurfacingCloseError drives
+// many concurrent callers through a transient failure at once. Before the
+// fix, every caller but the first would observe reconnect()'s old.Close()
+// and return a plain "port is closed" error instead of waiting for the new
+// client.
+func TestAutoReconnectConcurrentCallersBlockInsteadOfSurfacingCloseError(t *testing.T) {
+	stale := &fakeClient{romVersionErr: io.EOF}
+	fresh := &fakeClient{}
+
+	var dialCount int32
+	dial := func() (api.Client, error) {
+		n := atomic.AddInt32(&dialCount, 1)
+		if n == 1 {
+			return stale, nil
+		}
+		time.Sleep(20 * time.Millisecond)
+		return fresh, nil
+	}
+
+	dispenser, err := api.NewAutoReconnectDispenser(dial, api.ReconnectConfig{RetryBackoff: noBackoff})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer dispenser.Close()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, errs[i] = dispenser.RomVersion()
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("caller %d got error %v, want nil (it should have blocked for the reconnect)", i, err)
+		}
+	}
+}
+
+// TestAutoReconnectConcurrentCallersAllSeeGiveUpError drives many concurrent
+// callers through a reconnect that's doomed to exhaust MaxAttempts. Before
+// the fix, only the caller that actually drove the reconnect got the
+// informative give-up error; every caller that merely waited on it got nil
+// back and fell through to a redundant call against the still-stale
+// client, surfacing a bare transient error instead.
+func TestAutoReconnectConcurrentCallersAllSeeGiveUpError(t *testing.T) {
+	stale := &fakeClient{romVersionErr: io.EOF}
+
+	dialCount := 0
+	dial := func() (api.Client, error) {
+		dialCount++
+		if dialCount == 1 {
+			return stale, nil
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil, errors.New("dial failed")
+	}
+
+	dispenser, err := api.NewAutoReconnectDispenser(dial, api.ReconnectConfig{
+		RetryBackoff: noBackoff,
+		MaxAttempts:  1,
+	})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer dispenser.Close()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, errs[i] = dispenser.RomVersion()
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil || err == io.EOF {
+			t.Fatalf("caller %d got error %v, want the reconnect give-up error", i, err)
+		}
+	}
+}
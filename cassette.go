@@ -0,0 +1,10 @@
+package lcdm_2000_api
+
+// Cassette identifies which bill cassette a dual-purpose command (test
+// dispense, sensor diagnostic) targets.
+type Cassette int
+
+const (
+	UpperCassette Cassette = iota
+	LowerCassette
+)
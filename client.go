@@ -0,0 +1,207 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Client exposes the high-level LCDM-2000 commands on top of any
+// Transporter, so callers can target a real serial port, a TCP-to-serial
+// gateway, or a mock transport in tests without changing command code.
+// Every command has a Ctx variant accepting a context.Context for
+// cancellation; the plain variant just calls it with context.Background().
+type Client interface {
+	Status() (StatusCode, SensorStatus, error)
+	StatusCtx(ctx context.Context) (StatusCode, SensorStatus, error)
+	Reset() error
+	ResetCtx(ctx context.Context) error
+	Purge() error
+	PurgeCtx(ctx context.Context) error
+	UpperDispense(count byte) (DispenseResult, error)
+	UpperDispenseCtx(ctx context.Context, count byte) (DispenseResult, error)
+	LowerDispense(count byte) (DispenseResult, error)
+	LowerDispenseCtx(ctx context.Context, count byte) (DispenseResult, error)
+	Dispense(upperCount byte, lowerCount byte) (DispenseResult, error)
+	DispenseCtx(ctx context.Context, upperCount byte, lowerCount byte) (DispenseResult, error)
+	TestDispense(cassette Cassette, count byte) (DispenseResult, error)
+	TestDispenseCtx(ctx context.Context, cassette Cassette, count byte) (DispenseResult, error)
+	SensorDiagnostic(cassette Cassette) (SensorStatus, error)
+	SensorDiagnosticCtx(ctx context.Context, cassette Cassette) (SensorStatus, error)
+	Settings() (Settings, error)
+	SettingsCtx(ctx context.Context) (Settings, error)
+	RomVersion() (string, string, error)
+	RomVersionCtx(ctx context.Context) (string, string, error)
+	Close() error
+
+	// Stats returns a snapshot of the link-layer and command counters
+	// collected since the Client was created.
+	Stats() DispenserStats
+
+	// RegisterObserver subscribes fn to every counter update, so callers
+	// can wire it into Prometheus or any other metrics sink without
+	// polling Stats().
+	RegisterObserver(fn func(StatEvent))
+
+	// Events returns unsolicited frames the device pushed without a
+	// matching request, e.g. periodic sensor snapshots. It is nil if the
+	// underlying Transporter doesn't support this.
+	Events() <-chan Event
+}
+
+type client struct {
+	packager    Packager
+	transporter Transporter
+	stats       *stats
+}
+
+// NewClient builds a Client around an already-connected Transporter, using
+// the standard LCDM-2000 framing.
+func NewClient(transporter Transporter) Client {
+	return &client{packager: &lcdmPackager{}, transporter: transporter, stats: newStats()}
+}
+
+// NewConnection opens a serial port at path/baud and returns a ready-to-use
+// Client. logging, when true, prints every frame sent and received.
+func NewConnection(path string, baud Baud, logging bool, timeout time.Duration) (Client, error) {
+	s := newStats()
+	t := newSerialTransporter(path, baud, timeout, logging, s)
+
+	if err := t.Connect(); err != nil {
+		return nil, err
+	}
+
+	return &client{packager: &lcdmPackager{}, transporter: t, stats: s}, nil
+}
+
+func (c *client) Stats() DispenserStats {
+	return c.stats.snapshot()
+}
+
+func (c *client) RegisterObserver(fn func(StatEvent)) {
+	c.stats.registerObserver(fn)
+}
+
+func (c *client) Events() <-chan Event {
+	if es, ok := c.transporter.(EventSource); ok {
+		return es.Events()
+	}
+
+	return nil
+}
+
+func (c *client) Close() error {
+	return c.transporter.Close()
+}
+
+func (c *client) send(cmd byte, data []byte) ([]byte, error) {
+	return c.sendCtx(context.Background(), cmd, data)
+}
+
+func (c *client) sendCtx(ctx context.Context, cmd byte, data []byte) ([]byte, error) {
+	start := time.Now()
+	defer func() { c.stats.recordCommandLatency(cmd, time.Since(start)) }()
+
+	aduRequest, err := c.packager.Encode(cmd, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aduResponse, err := c.transporter.SendCtx(ctx, aduRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.packager.Verify(aduRequest, aduResponse); err != nil {
+		if errors.Is(err, ErrChecksumMismatch) {
+			c.stats.recordChecksumMismatch()
+		} else {
+			c.stats.recordMalformedFrame()
+		}
+		return nil, err
+	}
+
+	return c.packager.Decode(aduResponse)
+}
+
+func (c *client) Status() (StatusCode, SensorStatus, error) {
+	return c.StatusCtx(context.Background())
+}
+
+func (c *client) StatusCtx(ctx context.Context) (StatusCode, SensorStatus, error) {
+	response, err := c.sendCtx(ctx, 0x46, []byte{})
+
+	if err != nil {
+		return 0, SensorStatus{}, err
+	}
+
+	status, err := parseSensorStatus(response)
+
+	if err != nil {
+		return 0, SensorStatus{}, err
+	}
+
+	code := StatusCode(response[1])
+	c.stats.recordStatusCode(code)
+
+	return code, status, nil
+}
+
+func (c *client) Reset() error {
+	return c.ResetCtx(context.Background())
+}
+
+func (c *client) ResetCtx(ctx context.Context) error {
+	_, err := c.sendCtx(ctx, 0x44, []byte{})
+
+	return err
+}
+
+func (c *client) RomVersion() (string, string, error) {
+	return c.RomVersionCtx(context.Background())
+}
+
+func (c *client) RomVersionCtx(ctx context.Context) (string, string, error) {
+	response, err := c.sendCtx(ctx, 0x47, []byte{})
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(response) < 8 {
+		return "", "", fmt.Errorf("rom version response too short")
+	}
+
+	return string(response[2:4]), string(response[4:8]), nil
+}
+
+// parseSensorStatus decodes the sensor bitmap shared by Status and
+// SensorDiagnostic: byte 2 and byte 3 of the payload carry one bit per
+// sensor.
+func parseSensorStatus(response []byte) (SensorStatus, error) {
+	if len(response) < 4 {
+		return SensorStatus{}, fmt.Errorf("sensor status response too short")
+	}
+
+	status := SensorStatus{}
+
+	status.CheckSensor1 = (response[2] & (1 << 0)) != 0
+	status.CheckSensor2 = (response[2] & (1 << 1)) != 0
+	status.CheckSensor3 = (response[3] & (1 << 3)) != 0
+	status.CheckSensor4 = (response[3] & (1 << 4)) != 0
+	status.DivertSensor1 = (response[2] & (1 << 2)) != 0
+	status.DivertSensor2 = (response[2] & (1 << 3)) != 0
+	status.EjectSensor = (response[2] & (1 << 4)) != 0
+	status.ExitSensor = (response[2] & (1 << 5)) != 0
+	status.SolenoidSensor = (response[3] & (1 << 0)) != 0
+	status.UpperNearEnd = (response[2] & (1 << 6)) != 0
+	status.LowerNearEnd = (response[3] & (1 << 5)) != 0
+	status.CashBoxUpper = (response[3] & (1 << 1)) != 0
+	status.CashBoxLower = (response[3] & (1 << 2)) != 0
+	status.RejectTray = (response[3] & (1 << 6)) != 0
+
+	return status, nil
+}
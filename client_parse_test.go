@@ -0,0 +1,48 @@
+package lcdm_2000_api
+
+import "testing"
+
+func TestParseSensorStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		want     SensorStatus
+		wantErr  bool
+	}{
+		{
+			name:     "response too short",
+			response: []byte{0x00, 0x00, 0x00},
+			wantErr:  true,
+		},
+		{
+			name:     "decodes check and divert sensors",
+			response: []byte{0x00, 0x00, 0x01 | 0x04, 0x08},
+			want: SensorStatus{
+				CheckSensor1:  true,
+				DivertSensor1: true,
+				CheckSensor3:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSensorStatus(tt.response)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
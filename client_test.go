@@ -0,0 +1,99 @@
+package lcdm_2000_api_test
+
+import (
+	"context"
+	"testing"
+
+	api "lcdm_2000_api"
+)
+
+// fakeTransporter is an in-memory Transporter stand-in, so Client behavior
+// can be exercised without a real COM port.
+type fakeTransporter struct {
+	response []byte
+	err      error
+}
+
+func (f *fakeTransporter) Send(aduRequest []byte) ([]byte, error) {
+	return f.response, f.err
+}
+
+func (f *fakeTransporter) SendCtx(ctx context.Context, aduRequest []byte) ([]byte, error) {
+	return f.response, f.err
+}
+
+func (f *fakeTransporter) Connect() error { return nil }
+func (f *fakeTransporter) Close() error   { return nil }
+
+// buildResponse wraps payload in the LCDM-2000 response framing
+// (ResponseStart/CommunicationIdentify/TextStart/TextEnd + XOR checksum)
+// so it round-trips through Packager.Verify and Decode like a real frame.
+func buildResponse(payload []byte) []byte {
+	body := []byte{api.ResponseStart, api.CommunicationIdentify, api.TextStart, 0x00}
+	body = append(body, payload...)
+	body = append(body, api.TextEnd)
+
+	var crc byte
+	for _, b := range body {
+		crc ^= b
+	}
+
+	return append(body, crc)
+}
+
+func TestClientRomVersionOverFakeTransport(t *testing.T) {
+	transporter := &fakeTransporter{response: buildResponse([]byte("00012024"))}
+	client := api.NewClient(transporter)
+
+	version, buildDate, err := client.RomVersion()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if version != "01" {
+		t.Fatalf("got version %q, want %q", version, "01")
+	}
+
+	if buildDate != "2024" {
+		t.Fatalf("got build date %q, want %q", buildDate, "2024")
+	}
+}
+
+func TestClientRomVersionSurfacesShortResponse(t *testing.T) {
+	transporter := &fakeTransporter{response: buildResponse([]byte("0001"))}
+	client := api.NewClient(transporter)
+
+	_, _, err := client.RomVersion()
+
+	if err == nil {
+		t.Fatalf("expected an error for a too-short rom version response")
+	}
+}
+
+func TestClientUpperDispenseOverFakeTransport(t *testing.T) {
+	payload := append([]byte("0505"), byte(api.Good), byte(api.Normal))
+	transporter := &fakeTransporter{response: buildResponse(payload)}
+	client := api.NewClient(transporter)
+
+	result, err := client.UpperDispense(5)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != api.Good || result.UpperRequested != 5 || result.UpperDispensed != 5 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestClientUpperDispenseSurfacesNack(t *testing.T) {
+	transporter := &fakeTransporter{err: api.ErrNack}
+	client := api.NewClient(transporter)
+
+	_, err := client.UpperDispense(5)
+
+	if err != api.ErrNack {
+		t.Fatalf("got error %v, want %v", err, api.ErrNack)
+	}
+}
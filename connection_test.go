@@ -0,0 +1,36 @@
+package lcdm_2000_api_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	api "lcdm_2000_api"
+)
+
+// TestConnectionIntegration exercises a real LCDM-2000 over its serial
+// port. CI has no dispenser attached, so it only runs when
+// LCDM_SERIAL_PORT names the device to talk to.
+func TestConnectionIntegration(t *testing.T) {
+	path := os.Getenv("LCDM_SERIAL_PORT")
+
+	if path == "" {
+		t.Skip("LCDM_SERIAL_PORT not set; skipping hardware integration test")
+	}
+
+	c, err := api.NewConnection(path, api.Baud9600, true, 3*time.Second)
+
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+
+	result, err := c.UpperDispense(1)
+
+	if err != nil {
+		t.Fatalf("upper dispense: %v", err)
+	}
+
+	fmt.Println(result)
+}
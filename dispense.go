@@ -0,0 +1,81 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// cmdDispense is the dual-cassette dispense command. It used to be
+// mistakenly sent as cmdLowerDispense (0x55); the LCDM-2000 protocol
+// documents a distinct code for dispensing from both cassettes in one
+// request.
+const cmdDispense byte = 0x65
+
+func (c *client) Dispense(upperCount byte, lowerCount byte) (DispenseResult, error) {
+	return c.DispenseCtx(context.Background(), upperCount, lowerCount)
+}
+
+func (c *client) DispenseCtx(ctx context.Context, upperCount byte, lowerCount byte) (DispenseResult, error) {
+	response, err := c.sendCtx(ctx, cmdDispense, []byte(fmt.Sprintf("%02d%02d", upperCount, lowerCount)))
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	result, err := parseDispenseResult(response)
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	c.stats.recordStatusCode(result.Status)
+
+	return result, nil
+}
+
+// parseDispenseResult decodes a dual-cassette dispense response: 2-digit
+// ASCII requested/dispensed counts for the upper cassette, then the same
+// pair for the lower cassette, then the status and cashbox status bytes.
+func parseDispenseResult(response []byte) (DispenseResult, error) {
+	if len(response) < 10 {
+		return DispenseResult{}, fmt.Errorf("dispense response too short")
+	}
+
+	upperRequested, err := strconv.ParseUint(string(response[0:2]), 10, 8)
+
+	if err != nil {
+		return DispenseResult{}, fmt.Errorf("invalid upper requested count: %w", err)
+	}
+
+	upperDispensed, err := strconv.ParseUint(string(response[2:4]), 10, 8)
+
+	if err != nil {
+		return DispenseResult{}, fmt.Errorf("invalid upper dispensed count: %w", err)
+	}
+
+	lowerRequested, err := strconv.ParseUint(string(response[4:6]), 10, 8)
+
+	if err != nil {
+		return DispenseResult{}, fmt.Errorf("invalid lower requested count: %w", err)
+	}
+
+	lowerDispensed, err := strconv.ParseUint(string(response[6:8]), 10, 8)
+
+	if err != nil {
+		return DispenseResult{}, fmt.Errorf("invalid lower dispensed count: %w", err)
+	}
+
+	result := DispenseResult{
+		Status:         StatusCode(response[8]),
+		Cashbox:        CashboxStatusCode(response[9]),
+		UpperRequested: uint8(upperRequested),
+		UpperDispensed: uint8(upperDispensed),
+		LowerRequested: uint8(lowerRequested),
+		LowerDispensed: uint8(lowerDispensed),
+	}
+	result.RejectedByCassette[0] = rejectedCount(result.UpperRequested, result.UpperDispensed)
+	result.RejectedByCassette[1] = rejectedCount(result.LowerRequested, result.LowerDispensed)
+
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package lcdm_2000_api
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DispenseResult is the parsed outcome of a dispense command. Requested is
+// the count the device echoes back for what it was asked to dispense;
+// Dispensed is how many notes actually left the cassette.
+// RejectedByCassette[0] and [1] are the upper and lower cassette's
+// shortfall (Requested - Dispensed), zero when nothing was rejected.
+type DispenseResult struct {
+	Status             StatusCode
+	Cashbox            CashboxStatusCode
+	UpperRequested     uint8
+	UpperDispensed     uint8
+	LowerRequested     uint8
+	LowerDispensed     uint8
+	RejectedByCassette [2]uint8
+}
+
+func rejectedCount(requested, dispensed uint8) uint8 {
+	if requested <= dispensed {
+		return 0
+	}
+
+	return requested - dispensed
+}
+
+// parseCassetteDispenseResult decodes a single-cassette dispense response:
+// a 2-digit ASCII requested count, a 2-digit ASCII dispensed count, then
+// the status and cashbox status bytes.
+func parseCassetteDispenseResult(response []byte, cassette Cassette) (DispenseResult, error) {
+	if len(response) < 6 {
+		return DispenseResult{}, fmt.Errorf("dispense response too short")
+	}
+
+	requested, err := strconv.ParseUint(string(response[0:2]), 10, 8)
+
+	if err != nil {
+		return DispenseResult{}, fmt.Errorf("invalid requested count: %w", err)
+	}
+
+	dispensed, err := strconv.ParseUint(string(response[2:4]), 10, 8)
+
+	if err != nil {
+		return DispenseResult{}, fmt.Errorf("invalid dispensed count: %w", err)
+	}
+
+	result := DispenseResult{
+		Status:  StatusCode(response[4]),
+		Cashbox: CashboxStatusCode(response[5]),
+	}
+
+	switch cassette {
+	case UpperCassette:
+		result.UpperRequested = uint8(requested)
+		result.UpperDispensed = uint8(dispensed)
+		result.RejectedByCassette[0] = rejectedCount(result.UpperRequested, result.UpperDispensed)
+	case LowerCassette:
+		result.LowerRequested = uint8(requested)
+		result.LowerDispensed = uint8(dispensed)
+		result.RejectedByCassette[1] = rejectedCount(result.LowerRequested, result.LowerDispensed)
+	}
+
+	return result, nil
+}
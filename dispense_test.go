@@ -0,0 +1,64 @@
+package lcdm_2000_api
+
+import "testing"
+
+func TestParseDispenseResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		want     DispenseResult
+		wantErr  bool
+	}{
+		{
+			name:     "both cassettes fully dispensed",
+			response: append([]byte("05051010"), byte(Good), byte(Normal)),
+			want: DispenseResult{
+				Status:         Good,
+				Cashbox:        Normal,
+				UpperRequested: 5,
+				UpperDispensed: 5,
+				LowerRequested: 10,
+				LowerDispensed: 10,
+			},
+		},
+		{
+			name:     "both cassettes short-dispense",
+			response: append([]byte("05031007"), byte(BillCountError), byte(NearEnd)),
+			want: DispenseResult{
+				Status:             BillCountError,
+				Cashbox:            NearEnd,
+				UpperRequested:     5,
+				UpperDispensed:     3,
+				LowerRequested:     10,
+				LowerDispensed:     7,
+				RejectedByCassette: [2]uint8{2, 3},
+			},
+		},
+		{
+			name:     "response too short",
+			response: []byte("0503100"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDispenseResult(tt.response)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"fmt"
+)
+
+const cmdLowerDispense byte = 0x55
+
+func (c *client) LowerDispense(count byte) (DispenseResult, error) {
+	return c.LowerDispenseCtx(context.Background(), count)
+}
+
+func (c *client) LowerDispenseCtx(ctx context.Context, count byte) (DispenseResult, error) {
+	response, err := c.sendCtx(ctx, cmdLowerDispense, []byte(fmt.Sprintf("%02d", count)))
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	result, err := parseCassetteDispenseResult(response, LowerCassette)
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	c.stats.recordStatusCode(result.Status)
+
+	return result, nil
+}
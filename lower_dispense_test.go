@@ -0,0 +1,60 @@
+package lcdm_2000_api
+
+import "testing"
+
+func TestParseLowerDispenseResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		want     DispenseResult
+		wantErr  bool
+	}{
+		{
+			name:     "all requested notes dispensed",
+			response: dispenseFrame("10", "10", Good, Normal),
+			want: DispenseResult{
+				Status:         Good,
+				Cashbox:        Normal,
+				LowerRequested: 10,
+				LowerDispensed: 10,
+			},
+		},
+		{
+			name:     "short dispense is reflected as a reject",
+			response: dispenseFrame("10", "07", LowerBillEnd, NearEnd),
+			want: DispenseResult{
+				Status:             LowerBillEnd,
+				Cashbox:            NearEnd,
+				LowerRequested:     10,
+				LowerDispensed:     7,
+				RejectedByCassette: [2]uint8{0, 3},
+			},
+		},
+		{
+			name:     "response too short",
+			response: []byte("10"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCassetteDispenseResult(tt.response, LowerCassette)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package lcdm_2000_api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrMalformedFrame is returned by Verify when a response frame doesn't
+// carry the expected LCDM-2000 header/footer bytes.
+var ErrMalformedFrame = errors.New("response format invalid")
+
+// ErrChecksumMismatch is returned by Verify when a response frame's
+// trailing XOR checksum doesn't match its payload.
+var ErrChecksumMismatch = errors.New("response verification failed")
+
+// Packager specifies LCDM-2000 frame construction and parsing, independent
+// of how the ADU actually reaches the device.
+type Packager interface {
+	Encode(cmd byte, data []byte) (aduRequest []byte, err error)
+	Decode(aduResponse []byte) (payload []byte, err error)
+	Verify(aduRequest []byte, aduResponse []byte) error
+}
+
+// lcdmPackager implements the RequestStart/CommunicationIdentify/TextStart/
+// TextEnd framing and XOR checksum used by the LCDM-2000 protocol.
+type lcdmPackager struct{}
+
+func (p *lcdmPackager) Encode(cmd byte, data []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	_ = binary.Write(buf, binary.LittleEndian, RequestStart)
+	_ = binary.Write(buf, binary.LittleEndian, CommunicationIdentify)
+	_ = binary.Write(buf, binary.LittleEndian, TextStart)
+	_ = binary.Write(buf, binary.LittleEndian, cmd)
+	_ = binary.Write(buf, binary.LittleEndian, data)
+	_ = binary.Write(buf, binary.LittleEndian, TextEnd)
+
+	crc := getChecksum(buf.Bytes())
+
+	_ = binary.Write(buf, binary.LittleEndian, crc)
+
+	return buf.Bytes(), nil
+}
+
+func (p *lcdmPackager) Verify(aduRequest []byte, aduResponse []byte) error {
+	if len(aduResponse) < 6 {
+		return ErrMalformedFrame
+	}
+
+	if aduResponse[0] != ResponseStart || aduResponse[1] != CommunicationIdentify {
+		return ErrMalformedFrame
+	}
+
+	crc := aduResponse[len(aduResponse)-1]
+	crc2 := getChecksum(aduResponse[:len(aduResponse)-1])
+
+	if crc != crc2 {
+		return ErrChecksumMismatch
+	}
+
+	body := aduResponse[:len(aduResponse)-1]
+
+	if body[2] != TextStart || body[len(body)-1] != TextEnd {
+		return ErrMalformedFrame
+	}
+
+	return nil
+}
+
+func (p *lcdmPackager) Decode(aduResponse []byte) ([]byte, error) {
+	body := aduResponse[:len(aduResponse)-1]
+
+	return body[4 : len(body)-1], nil
+}
+
+func getChecksum(data []byte) byte {
+	chksum := byte(0)
+
+	for _, b := range data {
+		chksum = chksum ^ b
+	}
+
+	return chksum
+}
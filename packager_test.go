@@ -0,0 +1,52 @@
+package lcdm_2000_api
+
+import "testing"
+
+func TestLcdmPackagerVerify(t *testing.T) {
+	p := &lcdmPackager{}
+
+	tests := []struct {
+		name     string
+		response []byte
+		wantErr  error
+	}{
+		{
+			name:     "too short to ever carry a payload",
+			response: append([]byte{ResponseStart, CommunicationIdentify, TextStart, TextEnd}, getChecksum([]byte{ResponseStart, CommunicationIdentify, TextStart, TextEnd})),
+			wantErr:  ErrMalformedFrame,
+		},
+		{
+			name: "minimal frame with an empty payload",
+			response: func() []byte {
+				body := []byte{ResponseStart, CommunicationIdentify, TextStart, 0x00, TextEnd}
+				return append(body, getChecksum(body))
+			}(),
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Verify(nil, tt.response)
+
+			if err != tt.wantErr {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			// Verify passing must guarantee Decode can't panic.
+			payload, decodeErr := p.Decode(tt.response)
+
+			if decodeErr != nil {
+				t.Fatalf("unexpected decode error: %v", decodeErr)
+			}
+
+			if len(payload) != 0 {
+				t.Fatalf("got payload %q, want empty", payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package lcdm_2000_api
+
+import "context"
+
+// cmdPurge runs the device's cassette purge cycle (clearing jammed or
+// rejected notes into the reject tray) without dispensing anything.
+const cmdPurge byte = 0x51
+
+func (c *client) Purge() error {
+	return c.PurgeCtx(context.Background())
+}
+
+func (c *client) PurgeCtx(ctx context.Context) error {
+	_, err := c.sendCtx(ctx, cmdPurge, []byte{})
+
+	return err
+}
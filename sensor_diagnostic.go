@@ -0,0 +1,31 @@
+package lcdm_2000_api
+
+import "context"
+
+// cmdUpperSensorDiagnostic/cmdLowerSensorDiagnostic read a cassette's
+// sensor bitmap on demand, using the same layout as the sensor bits read by
+// Status.
+const (
+	cmdUpperSensorDiagnostic byte = 0x48
+	cmdLowerSensorDiagnostic byte = 0x58
+)
+
+func (c *client) SensorDiagnostic(cassette Cassette) (SensorStatus, error) {
+	return c.SensorDiagnosticCtx(context.Background(), cassette)
+}
+
+func (c *client) SensorDiagnosticCtx(ctx context.Context, cassette Cassette) (SensorStatus, error) {
+	cmd := cmdUpperSensorDiagnostic
+
+	if cassette == LowerCassette {
+		cmd = cmdLowerSensorDiagnostic
+	}
+
+	response, err := c.sendCtx(ctx, cmd, []byte{})
+
+	if err != nil {
+		return SensorStatus{}, err
+	}
+
+	return parseSensorStatus(response)
+}
@@ -0,0 +1,53 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// cmdSettings reads the device's configured link and retry parameters.
+const cmdSettings byte = 0x49
+
+// Settings is the device's currently configured link and retry parameters,
+// as returned by the Settings query.
+type Settings struct {
+	Baud       Baud
+	RetryCount uint8
+}
+
+func (c *client) Settings() (Settings, error) {
+	return c.SettingsCtx(context.Background())
+}
+
+func (c *client) SettingsCtx(ctx context.Context) (Settings, error) {
+	response, err := c.sendCtx(ctx, cmdSettings, []byte{})
+
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return parseSettings(response)
+}
+
+// parseSettings decodes a 2-digit ASCII retry count followed by a single
+// baud-rate selector byte ('0' for 9600, '1' for 19200).
+func parseSettings(response []byte) (Settings, error) {
+	if len(response) < 3 {
+		return Settings{}, fmt.Errorf("settings response too short")
+	}
+
+	retryCount, err := strconv.ParseUint(string(response[0:2]), 10, 8)
+
+	if err != nil {
+		return Settings{}, fmt.Errorf("invalid retry count: %w", err)
+	}
+
+	baud := Baud9600
+
+	if response[2] == '1' {
+		baud = Baud19200
+	}
+
+	return Settings{Baud: baud, RetryCount: uint8(retryCount)}, nil
+}
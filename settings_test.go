@@ -0,0 +1,49 @@
+package lcdm_2000_api
+
+import "testing"
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		want     Settings
+		wantErr  bool
+	}{
+		{
+			name:     "9600 baud",
+			response: []byte("050"),
+			want:     Settings{Baud: Baud9600, RetryCount: 5},
+		},
+		{
+			name:     "19200 baud",
+			response: []byte("101"),
+			want:     Settings{Baud: Baud19200, RetryCount: 10},
+		},
+		{
+			name:     "response too short",
+			response: []byte("05"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSettings(tt.response)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
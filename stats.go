@@ -0,0 +1,183 @@
+package lcdm_2000_api
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StatEventKind identifies what kind of link-layer or command-level event a
+// StatEvent carries.
+type StatEventKind int
+
+const (
+	EventRequestSent StatEventKind = iota
+	EventAck
+	EventNack
+	EventEot
+	EventChecksumMismatch
+	EventMalformedFrame
+	EventReadTimeoutRetry
+	EventStatusCode
+	EventCommandLatency
+)
+
+// StatEvent is pushed to every observer registered via RegisterObserver as
+// each counter below is updated, so callers can wire it into Prometheus (or
+// any other sink) without polling Stats().
+type StatEvent struct {
+	Kind       StatEventKind
+	StatusCode StatusCode
+	Command    byte
+	Latency    time.Duration
+}
+
+// CommandLatency is a minimal running histogram (count/total/max) of
+// round-trip latency for a single command code.
+type CommandLatency struct {
+	Count uint64
+	Total time.Duration
+	Max   time.Duration
+}
+
+// DispenserStats is a point-in-time snapshot returned by Client.Stats(). The
+// maps are copies, safe to read without further synchronization.
+type DispenserStats struct {
+	RequestsSent       uint64
+	AcksReceived       uint64
+	NacksReceived      uint64
+	EotsReceived       uint64
+	ChecksumMismatches uint64
+	MalformedFrames    uint64
+	ReadTimeoutRetries uint64
+	StatusCounts       map[StatusCode]uint64
+	CommandLatencies   map[byte]CommandLatency
+}
+
+// stats holds the live counters backing DispenserStats and the transporter.
+// Plain counters are updated atomically; the two maps need a mutex since Go
+// has no atomic map type.
+type stats struct {
+	requestsSent       uint64
+	acksReceived       uint64
+	nacksReceived      uint64
+	eotsReceived       uint64
+	checksumMismatches uint64
+	malformedFrames    uint64
+	readTimeoutRetries uint64
+
+	mu               sync.Mutex
+	statusCounts     map[StatusCode]uint64
+	commandLatencies map[byte]CommandLatency
+
+	observersMu sync.Mutex
+	observers   []func(StatEvent)
+}
+
+func newStats() *stats {
+	return &stats{
+		statusCounts:     make(map[StatusCode]uint64),
+		commandLatencies: make(map[byte]CommandLatency),
+	}
+}
+
+func (s *stats) registerObserver(fn func(StatEvent)) {
+	s.observersMu.Lock()
+	defer s.observersMu.Unlock()
+
+	s.observers = append(s.observers, fn)
+}
+
+func (s *stats) notify(event StatEvent) {
+	s.observersMu.Lock()
+	observers := append([]func(StatEvent){}, s.observers...)
+	s.observersMu.Unlock()
+
+	for _, fn := range observers {
+		fn(event)
+	}
+}
+
+func (s *stats) recordRequestSent() {
+	atomic.AddUint64(&s.requestsSent, 1)
+	s.notify(StatEvent{Kind: EventRequestSent})
+}
+
+func (s *stats) recordAck() {
+	atomic.AddUint64(&s.acksReceived, 1)
+	s.notify(StatEvent{Kind: EventAck})
+}
+
+func (s *stats) recordNack() {
+	atomic.AddUint64(&s.nacksReceived, 1)
+	s.notify(StatEvent{Kind: EventNack})
+}
+
+func (s *stats) recordEot() {
+	atomic.AddUint64(&s.eotsReceived, 1)
+	s.notify(StatEvent{Kind: EventEot})
+}
+
+func (s *stats) recordChecksumMismatch() {
+	atomic.AddUint64(&s.checksumMismatches, 1)
+	s.notify(StatEvent{Kind: EventChecksumMismatch})
+}
+
+func (s *stats) recordMalformedFrame() {
+	atomic.AddUint64(&s.malformedFrames, 1)
+	s.notify(StatEvent{Kind: EventMalformedFrame})
+}
+
+func (s *stats) recordReadTimeoutRetry() {
+	atomic.AddUint64(&s.readTimeoutRetries, 1)
+	s.notify(StatEvent{Kind: EventReadTimeoutRetry})
+}
+
+func (s *stats) recordStatusCode(code StatusCode) {
+	s.mu.Lock()
+	s.statusCounts[code]++
+	s.mu.Unlock()
+
+	s.notify(StatEvent{Kind: EventStatusCode, StatusCode: code})
+}
+
+func (s *stats) recordCommandLatency(cmd byte, d time.Duration) {
+	s.mu.Lock()
+	entry := s.commandLatencies[cmd]
+	entry.Count++
+	entry.Total += d
+	if d > entry.Max {
+		entry.Max = d
+	}
+	s.commandLatencies[cmd] = entry
+	s.mu.Unlock()
+
+	s.notify(StatEvent{Kind: EventCommandLatency, Command: cmd, Latency: d})
+}
+
+func (s *stats) snapshot() DispenserStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statusCounts := make(map[StatusCode]uint64, len(s.statusCounts))
+	for k, v := range s.statusCounts {
+		statusCounts[k] = v
+	}
+
+	commandLatencies := make(map[byte]CommandLatency, len(s.commandLatencies))
+	for k, v := range s.commandLatencies {
+		commandLatencies[k] = v
+	}
+
+	return DispenserStats{
+		RequestsSent:       atomic.LoadUint64(&s.requestsSent),
+		AcksReceived:       atomic.LoadUint64(&s.acksReceived),
+		NacksReceived:      atomic.LoadUint64(&s.nacksReceived),
+		EotsReceived:       atomic.LoadUint64(&s.eotsReceived),
+		ChecksumMismatches: atomic.LoadUint64(&s.checksumMismatches),
+		MalformedFrames:    atomic.LoadUint64(&s.malformedFrames),
+		ReadTimeoutRetries: atomic.LoadUint64(&s.readTimeoutRetries),
+		StatusCounts:       statusCounts,
+		CommandLatencies:   commandLatencies,
+	}
+}
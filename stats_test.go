@@ -0,0 +1,78 @@
+package lcdm_2000_api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsCountersIncrement(t *testing.T) {
+	s := newStats()
+
+	s.recordRequestSent()
+	s.recordAck()
+	s.recordNack()
+	s.recordEot()
+	s.recordChecksumMismatch()
+	s.recordMalformedFrame()
+	s.recordReadTimeoutRetry()
+	s.recordStatusCode(Good)
+	s.recordStatusCode(Good)
+	s.recordCommandLatency(0x46, 10*time.Millisecond)
+	s.recordCommandLatency(0x46, 30*time.Millisecond)
+
+	snap := s.snapshot()
+
+	if snap.RequestsSent != 1 || snap.AcksReceived != 1 || snap.NacksReceived != 1 ||
+		snap.EotsReceived != 1 || snap.ChecksumMismatches != 1 || snap.MalformedFrames != 1 ||
+		snap.ReadTimeoutRetries != 1 {
+		t.Fatalf("unexpected counters: %+v", snap)
+	}
+
+	if snap.StatusCounts[Good] != 2 {
+		t.Fatalf("got status count %d, want 2", snap.StatusCounts[Good])
+	}
+
+	latency := snap.CommandLatencies[0x46]
+
+	if latency.Count != 2 || latency.Total != 40*time.Millisecond || latency.Max != 30*time.Millisecond {
+		t.Fatalf("unexpected latency: %+v", latency)
+	}
+}
+
+func TestStatsNotifiesObservers(t *testing.T) {
+	s := newStats()
+
+	var got []StatEventKind
+
+	s.registerObserver(func(e StatEvent) {
+		got = append(got, e.Kind)
+	})
+
+	s.recordRequestSent()
+	s.recordAck()
+
+	want := []StatEventKind{EventRequestSent, EventAck}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("event %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStatsSnapshotIsAnIndependentCopy(t *testing.T) {
+	s := newStats()
+
+	s.recordStatusCode(Good)
+
+	snap := s.snapshot()
+	snap.StatusCounts[Good] = 99
+
+	if count := s.snapshot().StatusCounts[Good]; count != 1 {
+		t.Fatalf("mutating a snapshot's map affected the live stats: got %d, want 1", count)
+	}
+}
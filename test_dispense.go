@@ -0,0 +1,42 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"fmt"
+)
+
+// cmdTestDispenseUpper/cmdTestDispenseLower run a dispense cycle without
+// counting notes into the exit tray, used to verify a cassette's feed
+// mechanism is working.
+const (
+	cmdTestDispenseUpper byte = 0x76
+	cmdTestDispenseLower byte = 0x77
+)
+
+func (c *client) TestDispense(cassette Cassette, count byte) (DispenseResult, error) {
+	return c.TestDispenseCtx(context.Background(), cassette, count)
+}
+
+func (c *client) TestDispenseCtx(ctx context.Context, cassette Cassette, count byte) (DispenseResult, error) {
+	cmd := cmdTestDispenseUpper
+
+	if cassette == LowerCassette {
+		cmd = cmdTestDispenseLower
+	}
+
+	response, err := c.sendCtx(ctx, cmd, []byte(fmt.Sprintf("%02d", count)))
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	result, err := parseCassetteDispenseResult(response, cassette)
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	c.stats.recordStatusCode(result.Status)
+
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package lcdm_2000_api_test
+
+import (
+	"context"
+	"testing"
+
+	api "lcdm_2000_api"
+)
+
+// recordingTransporter wraps fakeTransporter to also capture the last
+// command byte sent, so tests can assert TestDispense picks the right
+// command for a cassette without re-deriving the branch logic themselves.
+type recordingTransporter struct {
+	fakeTransporter
+	lastCmd byte
+}
+
+func (r *recordingTransporter) SendCtx(ctx context.Context, aduRequest []byte) ([]byte, error) {
+	if len(aduRequest) > 3 {
+		r.lastCmd = aduRequest[3]
+	}
+	return r.fakeTransporter.SendCtx(ctx, aduRequest)
+}
+
+func TestClientTestDispenseOverFakeTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		cassette api.Cassette
+		wantCmd  byte
+	}{
+		{name: "upper cassette", cassette: api.UpperCassette, wantCmd: 0x76},
+		{name: "lower cassette", cassette: api.LowerCassette, wantCmd: 0x77},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := append([]byte("0505"), byte(api.Good), byte(api.Normal))
+			transporter := &recordingTransporter{fakeTransporter: fakeTransporter{response: buildResponse(payload)}}
+			client := api.NewClient(transporter)
+
+			result, err := client.TestDispense(tt.cassette, 5)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if transporter.lastCmd != tt.wantCmd {
+				t.Fatalf("got command 0x%X, want 0x%X", transporter.lastCmd, tt.wantCmd)
+			}
+
+			if result.Status != api.Good {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tarm/serial"
+)
+
+// ErrNack is returned by Transporter.Send when the device answers a request
+// with a NACK byte instead of acknowledging it.
+var ErrNack = errors.New("request was NACKed by device")
+
+// ErrEot is returned by Transporter.Send when the device answers a request
+// with an EOT byte instead of acknowledging it.
+var ErrEot = errors.New("request was EOT'd by device")
+
+// idlePollInterval bounds how long a single idle read blocks while the
+// reader goroutine is waiting for unsolicited traffic. It is used as the
+// port's actual ReadTimeout so a queued request never waits behind a
+// multi-second poll; the configured command timeout is instead spent as a
+// budget of read attempts (see maxReads), which keeps the overall time a
+// command is allowed to wait for a response unchanged.
+const idlePollInterval = 50 * time.Millisecond
+
+// Transporter carries an already-framed ADU to the LCDM-2000 and back,
+// without knowing anything about command codes or payload layout.
+type Transporter interface {
+	Send(aduRequest []byte) (aduResponse []byte, err error)
+	SendCtx(ctx context.Context, aduRequest []byte) (aduResponse []byte, err error)
+	Connect() error
+	Close() error
+}
+
+// serialPort is the minimal surface serialTransporter needs from an open
+// port, implemented by *serial.Port in production and swappable for a fake
+// in tests.
+type serialPort interface {
+	Read(p []byte) (int, error)
+	Write(p []byte) (int, error)
+	Close() error
+}
+
+// serialTransporter implements Transporter over github.com/tarm/serial. A
+// single background goroutine owns the port: it services one in-flight
+// request at a time and, while idle, surfaces anything the device pushes
+// unprompted as an Event on unsolicitedCh, instead of the old busy-loop
+// that blocked the whole port on a single synchronous read.
+type serialTransporter struct {
+	config         *serial.Config
+	port           serialPort
+	commandTimeout time.Duration
+	logging        bool
+	stats          *stats
+
+	reqCh         chan *inflightRequest
+	unsolicitedCh chan Event
+	stopCh        chan struct{}
+	wg            sync.WaitGroup
+}
+
+func newSerialTransporter(path string, baud Baud, timeout time.Duration, logging bool, s *stats) *serialTransporter {
+	return &serialTransporter{
+		config: &serial.Config{
+			Name: path, Baud: int(baud), ReadTimeout: idlePollInterval, Parity: serial.ParityNone, StopBits: serial.Stop1,
+			Size: 8,
+		},
+		commandTimeout: timeout,
+		logging:        logging,
+		stats:          s,
+	}
+}
+
+// maxReads is how many idlePollInterval-sized read attempts a single
+// command response is allowed before giving up, so shrinking the port's
+// per-read timeout to idlePollInterval doesn't shrink how long callers are
+// willing to wait for a response overall.
+func (t *serialTransporter) maxReads() int {
+	if t.commandTimeout <= 0 {
+		return 1050
+	}
+
+	n := int(t.commandTimeout / idlePollInterval)
+
+	if n < 1 {
+		n = 1
+	}
+
+	return n
+}
+
+func (t *serialTransporter) Connect() error {
+	p, err := serial.OpenPort(t.config)
+
+	if err != nil {
+		return err
+	}
+
+	t.port = p
+	t.startReader()
+
+	return nil
+}
+
+func (t *serialTransporter) Close() error {
+	if t.port == nil {
+		return errors.New("port not opened")
+	}
+
+	t.stopReader()
+
+	err := t.port.Close()
+	t.port = nil
+
+	return err
+}
+
+func (t *serialTransporter) Send(aduRequest []byte) ([]byte, error) {
+	return t.SendCtx(context.Background(), aduRequest)
+}
+
+// SendCtx queues aduRequest for the reader goroutine and waits for the
+// correlated response, or for ctx to be cancelled. The reader's idle read
+// is bounded by idlePollInterval rather than the full command timeout, so a
+// request submitted while that read is in flight is picked up on the
+// goroutine's next iteration instead of waiting behind it.
+func (t *serialTransporter) SendCtx(ctx context.Context, aduRequest []byte) ([]byte, error) {
+	if t.port == nil {
+		return nil, errors.New("serial port is closed")
+	}
+
+	req := &inflightRequest{aduRequest: aduRequest, resultCh: make(chan sendResult, 1)}
+
+	select {
+	case t.reqCh <- req:
+	case <-t.stopCh:
+		return nil, ErrTransporterClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case res := <-req.resultCh:
+		return res.data, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// exchange performs the synchronous write/ACK/data round trip for a single
+// request. It must only be called from the reader goroutine.
+func (t *serialTransporter) exchange(aduRequest []byte) ([]byte, error) {
+	if t.logging {
+		fmt.Printf("-> %X\n", aduRequest)
+	}
+
+	if _, err := t.port.Write(aduRequest); err != nil {
+		return nil, err
+	}
+
+	t.stats.recordRequestSent()
+
+	resp, err := t.readRespCode()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp {
+	case NackResponse:
+		return nil, ErrNack
+	case EotResponse:
+		return nil, ErrEot
+	case AckResponse:
+		// fall through, a data frame follows
+	default:
+		return nil, fmt.Errorf("unrecognised response")
+	}
+
+	data, err := t.readRespData()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = t.port.Write([]byte{byte(AckResponse)})
+
+	time.Sleep(time.Millisecond * 200)
+
+	return data, nil
+}
+
+func (t *serialTransporter) readRespCode() (ResponseType, error) {
+	var buf []byte
+	innerBuf := make([]byte, 256)
+
+	totalRead := 0
+	readTriesCount := 0
+	maxReadCount := t.maxReads()
+
+	for {
+		readTriesCount += 1
+
+		if readTriesCount > maxReadCount {
+			return ErrorResponse, fmt.Errorf("Reads tries exceeded")
+		}
+
+		n, err := t.port.Read(innerBuf)
+
+		if err != nil {
+			return ErrorResponse, err
+		}
+
+		totalRead += n
+		buf = append(buf, innerBuf[:n]...)
+
+		if totalRead < 1 {
+			t.stats.recordReadTimeoutRetry()
+			continue
+		}
+		break
+	}
+
+	if buf[0] == byte(AckResponse) {
+		if t.logging {
+			fmt.Printf("<- ACK\n")
+		}
+		t.stats.recordAck()
+		return AckResponse, nil
+	}
+
+	if buf[0] == byte(NackResponse) {
+		if t.logging {
+			fmt.Printf("<- NAK\n")
+		}
+		t.stats.recordNack()
+		return NackResponse, nil
+	}
+
+	if buf[0] == byte(EotResponse) {
+		if t.logging {
+			fmt.Printf("<- EOT\n")
+		}
+		t.stats.recordEot()
+		return EotResponse, nil
+	}
+
+	return ErrorResponse, nil
+}
+
+func (t *serialTransporter) readRespData() ([]byte, error) {
+	var buf []byte
+	innerBuf := make([]byte, 256)
+
+	totalRead := 0
+	readTriesCount := 0
+	maxReadCount := t.maxReads()
+
+	lastRead := false
+
+	for {
+		readTriesCount += 1
+
+		if readTriesCount > maxReadCount {
+			return nil, fmt.Errorf("Reads tries exceeded")
+		}
+
+		n, err := t.port.Read(innerBuf)
+
+		if err != nil {
+			return nil, err
+		}
+
+		totalRead += n
+		buf = append(buf, innerBuf[:n]...)
+
+		if len(buf) > 2 && buf[len(buf)-2] == TextEnd {
+			lastRead = true
+		}
+
+		if lastRead == false {
+			t.stats.recordReadTimeoutRetry()
+			continue
+		}
+
+		break
+	}
+
+	if t.logging {
+		fmt.Printf("<- %X\n", buf)
+	}
+
+	return buf, nil
+}
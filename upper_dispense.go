@@ -0,0 +1,30 @@
+package lcdm_2000_api
+
+import (
+	"context"
+	"fmt"
+)
+
+const cmdUpperDispense byte = 0x45
+
+func (c *client) UpperDispense(count byte) (DispenseResult, error) {
+	return c.UpperDispenseCtx(context.Background(), count)
+}
+
+func (c *client) UpperDispenseCtx(ctx context.Context, count byte) (DispenseResult, error) {
+	response, err := c.sendCtx(ctx, cmdUpperDispense, []byte(fmt.Sprintf("%02d", count)))
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	result, err := parseCassetteDispenseResult(response, UpperCassette)
+
+	if err != nil {
+		return DispenseResult{}, err
+	}
+
+	c.stats.recordStatusCode(result.Status)
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package lcdm_2000_api
+
+import "testing"
+
+// dispenseFrame builds a single-cassette dispense response payload: a
+// 2-digit requested count, a 2-digit dispensed count, then the status and
+// cashbox status bytes.
+func dispenseFrame(requested, dispensed string, status StatusCode, cashbox CashboxStatusCode) []byte {
+	return append([]byte(requested+dispensed), byte(status), byte(cashbox))
+}
+
+func TestParseUpperDispenseResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		want     DispenseResult
+		wantErr  bool
+	}{
+		{
+			name:     "all requested notes dispensed",
+			response: dispenseFrame("05", "05", Good, Normal),
+			want: DispenseResult{
+				Status:         Good,
+				Cashbox:        Normal,
+				UpperRequested: 5,
+				UpperDispensed: 5,
+			},
+		},
+		{
+			name:     "short dispense is reflected as a reject",
+			response: dispenseFrame("05", "03", PickupError, NearEnd),
+			want: DispenseResult{
+				Status:             PickupError,
+				Cashbox:            NearEnd,
+				UpperRequested:     5,
+				UpperDispensed:     3,
+				RejectedByCassette: [2]uint8{2, 0},
+			},
+		},
+		{
+			name:     "response too short",
+			response: []byte("050"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCassetteDispenseResult(tt.response, UpperCassette)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}